@@ -0,0 +1,98 @@
+// Copyright (C) 2023, Berachain Foundation. All rights reserved.
+// See the file LICENSE for licensing terms.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package state
+
+import "github.com/berachain/stargazer/lib/common"
+
+// `accessList` is an in-memory, per-transaction EIP-2929/2930 access list. It is intentionally
+// not persisted to any store, since its lifetime is scoped to a single transaction and it is
+// rebuilt from scratch (via `Reset`) for every transaction.
+type accessList struct {
+	addresses map[common.Address]struct{}
+	slots     map[common.Address]map[common.Hash]struct{}
+}
+
+// `newAccessList` creates a new, empty `accessList`.
+func newAccessList() *accessList {
+	return &accessList{
+		addresses: make(map[common.Address]struct{}),
+		slots:     make(map[common.Address]map[common.Hash]struct{}),
+	}
+}
+
+// `AddAddress` adds an address to the access list, reporting whether it was newly added.
+func (al *accessList) AddAddress(addr common.Address) bool {
+	if _, present := al.addresses[addr]; present {
+		return false
+	}
+	al.addresses[addr] = struct{}{}
+	return true
+}
+
+// `AddSlot` adds a storage slot of addr to the access list, reporting whether the address and/or
+// the slot were newly added.
+func (al *accessList) AddSlot(addr common.Address, slot common.Hash) (addrMod bool, slotMod bool) {
+	addrMod = al.AddAddress(addr)
+
+	slots, present := al.slots[addr]
+	if !present {
+		slots = make(map[common.Hash]struct{})
+		al.slots[addr] = slots
+	}
+	if _, present = slots[slot]; present {
+		return addrMod, false
+	}
+	slots[slot] = struct{}{}
+	return addrMod, true
+}
+
+// `ContainsAddress` reports whether addr is in the access list.
+func (al *accessList) ContainsAddress(addr common.Address) bool {
+	_, present := al.addresses[addr]
+	return present
+}
+
+// `Contains` reports whether the given (addr, slot) pair is in the access list.
+func (al *accessList) Contains(addr common.Address, slot common.Hash) (addressPresent, slotPresent bool) {
+	addressPresent = al.ContainsAddress(addr)
+	if !addressPresent {
+		return false, false
+	}
+	slots, present := al.slots[addr]
+	if !present {
+		return true, false
+	}
+	_, slotPresent = slots[slot]
+	return true, slotPresent
+}
+
+// `DeleteAddress` removes addr from the access list. Only ever called when reverting a journal
+// entry for an address that was newly added.
+func (al *accessList) DeleteAddress(addr common.Address) {
+	delete(al.addresses, addr)
+}
+
+// `DeleteSlot` removes slot of addr from the access list. Only ever called when reverting a
+// journal entry for a slot that was newly added.
+func (al *accessList) DeleteSlot(addr common.Address, slot common.Hash) {
+	slots, present := al.slots[addr]
+	if !present {
+		return
+	}
+	delete(slots, slot)
+	if len(slots) == 0 {
+		delete(al.slots, addr)
+	}
+}
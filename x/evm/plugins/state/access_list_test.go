@@ -0,0 +1,70 @@
+// Copyright (C) 2023, Berachain Foundation. All rights reserved.
+// See the file LICENSE for licensing terms.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/berachain/stargazer/lib/common"
+)
+
+func TestAccessListAddAddressOnlyJournalsFirstAdd(t *testing.T) {
+	sp := newTestStatePlugin()
+	addr := common.BytesToAddress([]byte{0x1})
+
+	sp.AddAddressToAccessList(addr)
+	require.True(t, sp.AddressInAccessList(addr))
+	require.Equal(t, 1, sp.journal.length())
+
+	// adding the same address again is a no-op and must not journal a second entry.
+	sp.AddAddressToAccessList(addr)
+	require.Equal(t, 1, sp.journal.length())
+}
+
+func TestAccessListAddAddressRevert(t *testing.T) {
+	sp := newTestStatePlugin()
+	addr := common.BytesToAddress([]byte{0x1})
+
+	snapshot := sp.journal.length()
+	sp.AddAddressToAccessList(addr)
+	require.True(t, sp.AddressInAccessList(addr))
+
+	sp.journal.revert(sp, snapshot)
+
+	require.False(t, sp.AddressInAccessList(addr))
+}
+
+func TestAccessListAddSlotRevertsAddressAndSlotIndependently(t *testing.T) {
+	sp := newTestStatePlugin()
+	addr := common.BytesToAddress([]byte{0x1})
+	slot := common.BytesToHash([]byte{0x2})
+
+	// addr is already in the access list before the slot is added, so only the slot addition
+	// should be journaled (and reverted) here.
+	sp.AddAddressToAccessList(addr)
+	snapshot := sp.journal.length()
+	sp.AddSlotToAccessList(addr, slot)
+	addrPresent, slotPresent := sp.SlotInAccessList(addr, slot)
+	require.True(t, addrPresent)
+	require.True(t, slotPresent)
+
+	sp.journal.revert(sp, snapshot)
+
+	addrPresent, slotPresent = sp.SlotInAccessList(addr, slot)
+	require.True(t, addrPresent)
+	require.False(t, slotPresent)
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2023, Berachain Foundation. All rights reserved.
+// See the file LICENSE for licensing terms.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/berachain/stargazer/lib/common"
+)
+
+// TestAddBalanceKeeperErrorDoesNotPanic injects a mock bank keeper failure (MintCoins) and
+// verifies AddBalance surfaces it via stateErr instead of panicking.
+func TestAddBalanceKeeperErrorDoesNotPanic(t *testing.T) {
+	sp := newTestStatePlugin()
+	addr := common.BytesToAddress([]byte{0x1})
+	bk, ok := sp.bk.(*fakeBankKeeper)
+	require.True(t, ok)
+
+	bk.failNext = true
+	require.NotPanics(t, func() {
+		sp.AddBalance(addr, big.NewInt(100))
+	})
+
+	require.True(t, sp.HasStateError())
+	require.ErrorIs(t, sp.StateError(), errKeeperFailure)
+}
+
+// TestSubBalanceKeeperErrorDoesNotPanic injects a mock bank keeper failure
+// (SendCoinsFromAccountToModule) and verifies SubBalance surfaces it via stateErr.
+func TestSubBalanceKeeperErrorDoesNotPanic(t *testing.T) {
+	sp := newTestStatePlugin()
+	addr := common.BytesToAddress([]byte{0x1})
+	bk, ok := sp.bk.(*fakeBankKeeper)
+	require.True(t, ok)
+
+	bk.failNext = true
+	require.NotPanics(t, func() {
+		sp.SubBalance(addr, big.NewInt(100))
+	})
+
+	require.True(t, sp.HasStateError())
+}
+
+// TestStateErrorShortCircuitsSubsequentKeeperCalls verifies that once stateErr is set, further
+// balance mutations become no-ops rather than attempting (and possibly panicking on) more keeper
+// calls, so that the caller can unwind the failed message without additional side effects.
+func TestStateErrorShortCircuitsSubsequentKeeperCalls(t *testing.T) {
+	sp := newTestStatePlugin()
+	addr := common.BytesToAddress([]byte{0x1})
+	bk, ok := sp.bk.(*fakeBankKeeper)
+	require.True(t, ok)
+
+	bk.failNext = true
+	sp.AddBalance(addr, big.NewInt(100))
+	require.True(t, sp.HasStateError())
+
+	// bk is no longer armed to fail, but the plugin must still refuse to call it again.
+	before := sp.GetBalance(addr)
+	require.NotPanics(t, func() {
+		sp.AddBalance(addr, big.NewInt(100))
+	})
+	require.Equal(t, before, sp.GetBalance(addr))
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2023, Berachain Foundation. All rights reserved.
+// See the file LICENSE for licensing terms.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package state
+
+import (
+	"encoding/binary"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/berachain/stargazer/lib/common"
+)
+
+// codeRefKeyPrefix namespaces the refcount entry for a code hash, within the evm store.
+//
+// TODO: fold this into the canonical key prefix registry alongside CodeHashKeyFor/CodeKeyFor.
+var codeRefKeyPrefix = []byte{0x05}
+
+// `CodeRefKeyFor` returns the store key under which the reference count for codeHash is kept.
+func CodeRefKeyFor(codeHash common.Hash) []byte {
+	return append(append([]byte{}, codeRefKeyPrefix...), codeHash[:]...)
+}
+
+// `codeRefCount` returns the number of accounts currently referencing codeHash's code blob.
+func (sp *statePlugin) codeRefCount(codeHash common.Hash) uint64 {
+	bz := sp.cms.GetKVStore(sp.evmStoreKey).Get(CodeRefKeyFor(codeHash))
+	if len(bz) == 0 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(bz)
+}
+
+// `setCodeRefCount` sets the refcount for codeHash, deleting the entry entirely when it reaches
+// zero rather than persisting a zero-valued counter.
+func (sp *statePlugin) setCodeRefCount(codeHash common.Hash, count uint64) {
+	store := sp.cms.GetKVStore(sp.evmStoreKey)
+	if count == 0 {
+		store.Delete(CodeRefKeyFor(codeHash))
+		return
+	}
+	bz := make([]byte, 8) //nolint:gomnd // size of a uint64.
+	binary.BigEndian.PutUint64(bz, count)
+	store.Set(CodeRefKeyFor(codeHash), bz)
+}
+
+// `incCodeRef` increments the refcount for codeHash, writing code to the store only the first
+// time codeHash is referenced (i.e. when no other account currently shares this bytecode).
+func (sp *statePlugin) incCodeRef(codeHash common.Hash, code []byte) {
+	if codeHash == emptyCodeHash || codeHash == (common.Hash{}) {
+		return
+	}
+
+	count := sp.codeRefCount(codeHash)
+	if count == 0 {
+		sp.cms.GetKVStore(sp.evmStoreKey).Set(CodeKeyFor(codeHash), code)
+	}
+	sp.setCodeRefCount(codeHash, count+1)
+}
+
+// `decCodeRef` decrements the refcount for codeHash, deleting the underlying code blob once no
+// account references it anymore.
+func (sp *statePlugin) decCodeRef(codeHash common.Hash) {
+	if codeHash == emptyCodeHash || codeHash == (common.Hash{}) {
+		return
+	}
+
+	count := sp.codeRefCount(codeHash)
+	if count == 0 {
+		// already collected, or never refcounted (e.g. pre-migration data); nothing to do.
+		return
+	}
+
+	count--
+	sp.setCodeRefCount(codeHash, count)
+	if count == 0 {
+		sp.cms.GetKVStore(sp.evmStoreKey).Delete(CodeKeyFor(codeHash))
+	}
+}
+
+// `InitCodeRef` seeds the refcount for an account's code hash during genesis import, where code
+// is written directly instead of flowing through `SetCode`. Genesis import must call this once
+// per account, including accounts that share a code hash, so that the refcounts (and therefore
+// the garbage collection done by `decCodeRef`) stay accurate across export/import roundtrips.
+func (sp *statePlugin) InitCodeRef(codeHash common.Hash, code []byte) {
+	sp.incCodeRef(codeHash, code)
+}
+
+// `IterateCodeRefs` is the export-side counterpart to `InitCodeRef`: it walks every code hash with
+// a non-zero refcount and invokes cb with its hash and current refcount, stopping early if cb
+// returns false. Genesis export uses this to recover, for each code hash, how many accounts
+// reference it, without having to recompute refcounts from the account set.
+func (sp *statePlugin) IterateCodeRefs(cb func(codeHash common.Hash, refCount uint64) bool) {
+	store := sp.cms.GetKVStore(sp.evmStoreKey)
+	it := sdk.KVStorePrefixIterator(store, codeRefKeyPrefix)
+	defer it.Close()
+
+	for ; it.Valid(); it.Next() {
+		codeHash := common.BytesToHash(it.Key()[len(codeRefKeyPrefix):])
+		refCount := binary.BigEndian.Uint64(it.Value())
+		if !cb(codeHash, refCount) {
+			return
+		}
+	}
+}
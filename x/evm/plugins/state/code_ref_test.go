@@ -0,0 +1,130 @@
+// Copyright (C) 2023, Berachain Foundation. All rights reserved.
+// See the file LICENSE for licensing terms.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/berachain/stargazer/lib/common"
+)
+
+// TestRedeployIdenticalBytecodeSharesRefcount verifies that two accounts deployed with identical
+// bytecode share a single refcounted code blob, rather than each getting its own copy.
+func TestRedeployIdenticalBytecodeSharesRefcount(t *testing.T) {
+	sp := newTestStatePlugin()
+	addrA := common.BytesToAddress([]byte{0x1})
+	addrB := common.BytesToAddress([]byte{0x2})
+	code := []byte{0xAA, 0xBB, 0xCC}
+
+	sp.CreateAccount(addrA)
+	sp.SetCode(addrA, code)
+	sp.CreateAccount(addrB)
+	sp.SetCode(addrB, code)
+
+	codeHash := sp.GetCodeHash(addrA)
+	require.Equal(t, codeHash, sp.GetCodeHash(addrB))
+	require.EqualValues(t, 2, sp.codeRefCount(codeHash))
+	require.Equal(t, code, sp.GetCode(addrB))
+}
+
+// TestSuicideThenRecreateDoesNotCorruptSharedCode is the regression scenario from the review: a
+// suicided account shares a code hash with a still-live account. Deleting the suicided account
+// (which releases its reference) must decrement the refcount exactly once, and recreating it must
+// leave the refcount and the still-live account's code intact.
+func TestSuicideThenRecreateDoesNotCorruptSharedCode(t *testing.T) {
+	sp := newTestStatePlugin()
+	addrA := common.BytesToAddress([]byte{0x1})
+	addrB := common.BytesToAddress([]byte{0x2})
+	code := []byte{0xAA, 0xBB, 0xCC}
+
+	sp.CreateAccount(addrA)
+	sp.SetCode(addrA, code)
+	sp.CreateAccount(addrB)
+	sp.SetCode(addrB, code)
+	codeHash := sp.GetCodeHash(addrA)
+	require.EqualValues(t, 2, sp.codeRefCount(codeHash))
+
+	require.True(t, sp.Suicide(addrA))
+	sp.DeleteSuicides([]common.Address{addrA})
+	require.EqualValues(t, 1, sp.codeRefCount(codeHash))
+
+	// addrB, which never suicided or redeployed, must still have its code intact.
+	require.Equal(t, code, sp.GetCode(addrB))
+
+	// redeploying at addrA must not affect addrB's reference.
+	sp.CreateAccount(addrA)
+	sp.SetCode(addrA, []byte{0xDD})
+	require.Equal(t, code, sp.GetCode(addrB))
+	require.EqualValues(t, 1, sp.codeRefCount(codeHash))
+}
+
+// TestResetAccountRevertDoesNotCorruptSharedCodeRefcount is the regression test for the bug where
+// ResetAccount's decCodeRef ran outside the journal: reverting a redeploy over an account that
+// shared its code hash with another live account must re-increment the shared refcount exactly
+// back to where it was, not leave it (or the blob) permanently decremented.
+func TestResetAccountRevertDoesNotCorruptSharedCodeRefcount(t *testing.T) {
+	sp := newTestStatePlugin()
+	addrA := common.BytesToAddress([]byte{0x1})
+	addrB := common.BytesToAddress([]byte{0x2})
+	code := []byte{0xAA, 0xBB, 0xCC}
+
+	sp.CreateAccount(addrA)
+	sp.SetCode(addrA, code)
+	sp.CreateAccount(addrB)
+	sp.SetCode(addrB, code)
+	codeHash := sp.GetCodeHash(addrA)
+	require.EqualValues(t, 2, sp.codeRefCount(codeHash))
+
+	snapshot := sp.journal.length()
+	require.True(t, sp.Suicide(addrA))
+	sp.CreateAccount(addrA) // redeploy over the suicided addrA within the same sub-call
+	sp.SetCode(addrA, []byte{0xDD})
+
+	sp.journal.revert(sp, snapshot)
+
+	require.Equal(t, codeHash, sp.GetCodeHash(addrA))
+	require.Equal(t, code, sp.GetCode(addrA))
+	require.Equal(t, code, sp.GetCode(addrB))
+	require.EqualValues(t, 2, sp.codeRefCount(codeHash))
+}
+
+// TestCodeRefGenesisRoundtrip verifies that InitCodeRef (genesis import) and IterateCodeRefs
+// (genesis export) round-trip a code hash's refcount.
+func TestCodeRefGenesisRoundtrip(t *testing.T) {
+	sp := newTestStatePlugin()
+	code := []byte{0xAA, 0xBB}
+	codeHash := common.BytesToHash([]byte{0xFF})
+
+	sp.InitCodeRef(codeHash, code)
+	sp.InitCodeRef(codeHash, code)
+	sp.InitCodeRef(codeHash, code)
+
+	exported := make(map[common.Hash]uint64)
+	sp.IterateCodeRefs(func(h common.Hash, refCount uint64) bool {
+		exported[h] = refCount
+		return true
+	})
+
+	require.EqualValues(t, 3, exported[codeHash])
+
+	// re-importing into a fresh plugin from the exported refcount must reproduce the same state.
+	sp2 := newTestStatePlugin()
+	for i := uint64(0); i < exported[codeHash]; i++ {
+		sp2.InitCodeRef(codeHash, code)
+	}
+	require.Equal(t, sp.codeRefCount(codeHash), sp2.codeRefCount(codeHash))
+}
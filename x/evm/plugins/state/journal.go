@@ -0,0 +1,237 @@
+// Copyright (C) 2023, Berachain Foundation. All rights reserved.
+// See the file LICENSE for licensing terms.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package state
+
+import (
+	"math/big"
+
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+
+	"github.com/berachain/stargazer/lib/common"
+)
+
+// `journalEntry` is a single reversible mutation applied to the `statePlugin`. Every entry knows
+// how to undo itself against a `statePlugin`, without re-recording itself in the journal.
+type journalEntry interface {
+	// `revert` undoes the effects of this journal entry on sp.
+	revert(sp *statePlugin)
+}
+
+// `revision` ties a `snapshot.Controller` revision id to the length of the journal at the time the
+// revision was taken, so that `RevertToSnapshot` knows how far back to unwind the journal.
+type revision struct {
+	id           int
+	journalIndex int
+}
+
+// `journal` is an append-only log of every reversible mutation made to the `statePlugin` since the
+// last time it was reset. Unlike the `snapshot.Controller`, which snapshots the underlying
+// multi-store and event manager wholesale, the journal tracks individual state mutations
+// (balances, nonces, code, storage, etc.) so that a sub-call's failure can be undone without
+// disturbing writes made by its caller, either before or after the sub-call.
+type journal struct {
+	entries []journalEntry
+}
+
+// `newJournal` creates a new, empty `journal`.
+func newJournal() *journal {
+	return &journal{
+		entries: make([]journalEntry, 0),
+	}
+}
+
+// `append` records a new entry at the end of the journal.
+func (j *journal) append(entry journalEntry) {
+	j.entries = append(j.entries, entry)
+}
+
+// `length` returns the number of entries currently recorded in the journal.
+func (j *journal) length() int {
+	return len(j.entries)
+}
+
+// `revert` undoes every entry recorded after `snapshot`, in LIFO order, and then truncates the
+// journal back down to `snapshot` entries.
+func (j *journal) revert(sp *statePlugin, snapshot int) {
+	for i := len(j.entries) - 1; i >= snapshot; i-- {
+		j.entries[i].revert(sp)
+	}
+	j.entries = j.entries[:snapshot]
+}
+
+// =============================================================================
+// Journal Entries
+// =============================================================================
+
+// `createAccountChange` undoes a `CreateAccount`. `prevAccount` is the account that lived at
+// `account` immediately before `CreateAccount` overwrote it, or nil if the address was empty.
+type createAccountChange struct {
+	account     common.Address
+	prevAccount authtypes.AccountI
+}
+
+func (ch createAccountChange) revert(sp *statePlugin) {
+	if ch.prevAccount != nil {
+		// `account` was overwritten, not newly created: restore the auth account that was there
+		// before. Its storage/code/codehash are restored separately, by the `resetAccountChange`
+		// that `ResetAccount` pushed just before this entry (and which reverts right after this
+		// one, in LIFO order).
+		sp.ak.SetAccount(sp.ctx, ch.prevAccount)
+		return
+	}
+
+	// `account` was newly created: remove it, along with the codehash `CreateAccount` wrote.
+	if acc := sp.ak.GetAccount(sp.ctx, ch.account[:]); acc != nil {
+		sp.ak.RemoveAccount(sp.ctx, acc)
+	}
+	sp.cms.GetKVStore(sp.evmStoreKey).Delete(CodeHashKeyFor(ch.account))
+}
+
+// `storageSlot` is a (key, value) pair of a single storage slot, used to snapshot an account's
+// storage before `ResetAccount` wipes it.
+type storageSlot struct {
+	key   common.Hash
+	value common.Hash
+}
+
+// `resetAccountChange` undoes a `ResetAccount` by restoring every storage slot it wiped and the
+// code/codehash it released, including re-incrementing the code's refcount via `setCode`.
+type resetAccountChange struct {
+	account  common.Address
+	slots    []storageSlot
+	prevHash common.Hash
+	prevCode []byte
+}
+
+func (ch resetAccountChange) revert(sp *statePlugin) {
+	for _, slot := range ch.slots {
+		sp.setState(ch.account, slot.key, slot.value)
+	}
+
+	// `setCode` diffs against the account's current (post-reset) codehash, so this both restores
+	// the codehash/code and re-increments the refcount that `ResetAccount`'s `decCodeRef` took.
+	sp.setCode(ch.account, ch.prevHash, ch.prevCode)
+}
+
+// `balanceChange` undoes an `AddBalance` or `SubBalance`, by applying the opposite delta.
+type balanceChange struct {
+	account common.Address
+	amount  *big.Int
+	wasAdd  bool
+}
+
+func (ch balanceChange) revert(sp *statePlugin) {
+	if ch.wasAdd {
+		sp.subBalance(ch.account, ch.amount)
+	} else {
+		sp.addBalance(ch.account, ch.amount)
+	}
+}
+
+// `transferBalanceChange` undoes a `TransferBalance` by sending the amount back to the sender.
+type transferBalanceChange struct {
+	from, to common.Address
+	amount   *big.Int
+}
+
+func (ch transferBalanceChange) revert(sp *statePlugin) {
+	sp.transferBalance(ch.to, ch.from, ch.amount)
+}
+
+// `nonceChange` undoes a `SetNonce` by restoring the previous nonce.
+type nonceChange struct {
+	account common.Address
+	prev    uint64
+}
+
+func (ch nonceChange) revert(sp *statePlugin) {
+	sp.setNonce(ch.account, ch.prev)
+}
+
+// `codeChange` undoes a `SetCode` by restoring the previous code hash/code.
+type codeChange struct {
+	account  common.Address
+	prevHash common.Hash
+	prevCode []byte
+}
+
+func (ch codeChange) revert(sp *statePlugin) {
+	sp.setCode(ch.account, ch.prevHash, ch.prevCode)
+}
+
+// `storageChange` undoes a `SetState` by restoring the previous value of the slot.
+type storageChange struct {
+	account common.Address
+	key     common.Hash
+	prev    common.Hash
+}
+
+func (ch storageChange) revert(sp *statePlugin) {
+	sp.setState(ch.account, ch.key, ch.prev)
+}
+
+// `suicideChange` undoes a `Suicide` by clearing the suicided marker.
+//
+// NOTE: the actual removal of the account's storage/code/auth-account is deferred to
+// `DeleteSuicides`, which only runs after the outermost call frame succeeds, so undoing the
+// marker here is sufficient to make the account behave as if `Suicide` was never called.
+type suicideChange struct {
+	account common.Address
+}
+
+func (ch suicideChange) revert(sp *statePlugin) {
+	delete(sp.suicided, ch.account)
+}
+
+// `refundChange` undoes an `AddRefund`/`SubRefund` by restoring the previous refund counter.
+type refundChange struct {
+	prev uint64
+}
+
+func (ch refundChange) revert(sp *statePlugin) {
+	sp.refund = ch.prev
+}
+
+// `accessListAddAccountChange` undoes an `AddAddressToAccessList` for an address that was not
+// already present in the access list.
+type accessListAddAccountChange struct {
+	address common.Address
+}
+
+func (ch accessListAddAccountChange) revert(sp *statePlugin) {
+	sp.accessList.DeleteAddress(ch.address)
+}
+
+// `accessListAddSlotChange` undoes an `AddSlotToAccessList` for a slot that was not already
+// present in the access list.
+type accessListAddSlotChange struct {
+	address common.Address
+	slot    common.Hash
+}
+
+func (ch accessListAddSlotChange) revert(sp *statePlugin) {
+	sp.accessList.DeleteSlot(ch.address, ch.slot)
+}
+
+// `addLogChange` undoes an `AddLog` by popping the most recently appended log.
+//
+// NOTE: this only ever undoes a log appended after the snapshot being reverted to (by the
+// reverted call frame, or by a nested frame of it); logs appended before that snapshot are never
+// touched, since their journal entries lie below the snapshot index and are never replayed.
+type addLogChange struct{}
+
+func (ch addLogChange) revert(sp *statePlugin) {
+	sp.logs = sp.logs[:len(sp.logs)-1]
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2023, Berachain Foundation. All rights reserved.
+// See the file LICENSE for licensing terms.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/berachain/stargazer/lib/common"
+)
+
+// `spyEntry` is a `journalEntry` that records when it was reverted, so tests can assert on LIFO
+// ordering without depending on any real mutation.
+type spyEntry struct {
+	id    int
+	order *[]int
+}
+
+func (e spyEntry) revert(*statePlugin) {
+	*e.order = append(*e.order, e.id)
+}
+
+func TestJournalRevertLIFOOrder(t *testing.T) {
+	j := newJournal()
+	var order []int
+	j.append(spyEntry{id: 1, order: &order})
+	j.append(spyEntry{id: 2, order: &order})
+	j.append(spyEntry{id: 3, order: &order})
+
+	j.revert(&statePlugin{}, 0)
+
+	require.Equal(t, []int{3, 2, 1}, order)
+	require.Equal(t, 0, j.length())
+}
+
+func TestJournalRevertToSnapshotOnlyUndoesNewerEntries(t *testing.T) {
+	j := newJournal()
+	var order []int
+	j.append(spyEntry{id: 1, order: &order})
+	snapshot := j.length()
+	j.append(spyEntry{id: 2, order: &order})
+	j.append(spyEntry{id: 3, order: &order})
+
+	j.revert(&statePlugin{}, snapshot)
+
+	// only entries recorded after the snapshot are reverted, and in LIFO order.
+	require.Equal(t, []int{3, 2}, order)
+	require.Equal(t, snapshot, j.length())
+}
+
+// TestJournalSuicideRevert mimics the "try_set" pattern used by the EVM interpreter: a sub-call
+// mutates state (here, suicides an account), then the outer call reverts the sub-call's snapshot.
+// The suicide marker must be cleared, as if `Suicide` had never been called.
+func TestJournalSuicideRevert(t *testing.T) {
+	sp := newTestStatePlugin()
+	addr := common.BytesToAddress([]byte{0x1})
+	sp.ak.SetAccount(sp.ctx, sp.ak.NewAccountWithAddress(sp.ctx, addr[:]))
+
+	snapshot := sp.journal.length()
+	require.True(t, sp.Suicide(addr))
+	require.True(t, sp.HasSuicided(addr))
+
+	sp.journal.revert(sp, snapshot)
+
+	require.False(t, sp.HasSuicided(addr))
+}
+
+func TestJournalRefundRevert(t *testing.T) {
+	sp := newTestStatePlugin()
+
+	sp.AddRefund(10)
+	snapshot := sp.journal.length()
+	sp.AddRefund(5)
+	require.EqualValues(t, 15, sp.GetRefund())
+
+	sp.journal.revert(sp, snapshot)
+
+	require.EqualValues(t, 10, sp.GetRefund())
+}
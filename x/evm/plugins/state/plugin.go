@@ -16,12 +16,16 @@ package state
 
 import (
 	"context"
+	"fmt"
 	"math/big"
+	"sort"
 
 	storetypes "github.com/cosmos/cosmos-sdk/store/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 
 	ethstate "github.com/berachain/stargazer/eth/core/state"
+	ethtypes "github.com/berachain/stargazer/eth/core/types"
 	"github.com/berachain/stargazer/lib/common"
 	"github.com/berachain/stargazer/lib/crypto"
 	"github.com/berachain/stargazer/lib/snapshot"
@@ -86,6 +90,41 @@ type statePlugin struct {
 	// we load the evm denom in the constructor, to prevent going to
 	// the params to get it mid interpolation.
 	evmDenom string // TODO: get from params ( we have a store so like why not )
+
+	// journal tracks every reversible state mutation made by the plugin, so that a failed
+	// sub-call can undo its own mutations without disturbing the mutations made by its caller.
+	// `validRevisions` ties the `snapshot.Controller` revision ids handed out by `Snapshot()` to
+	// the position in the journal they were taken at, so `RevertToSnapshot()` can unwind both in
+	// lockstep.
+	journal        *journal
+	validRevisions []revision
+
+	// suicided tracks addresses that had `Suicide` called on them during the current tx. The
+	// accounts themselves are not removed until `DeleteSuicides` is called by the caller once the
+	// outermost call frame has succeeded.
+	suicided map[common.Address]struct{}
+
+	// refund is the currently accumulated gas refund, mutated by `AddRefund`/`SubRefund`.
+	refund uint64
+
+	// accessList is the EIP-2929/2930 access list for the current transaction.
+	accessList *accessList
+
+	// stateErr is set the first time a balance or nonce mutation gets an error back from a bank
+	// or account keeper call (e.g. a recoverable bank module error). Once set, `addBalance`,
+	// `subBalance`, `transferBalance` and `setNonce` become no-ops, so that the caller can finish
+	// unwinding the current message and surface the error as a failed transaction, instead of the
+	// node panicking. Other methods (e.g. `CreateAccount`, `ResetAccount`, `DeleteSuicides` and the
+	// getters) do not check stateErr and still call the keepers.
+	stateErr error
+
+	// txConfig carries the block/tx identifiers used to stamp logs produced during the current
+	// transaction, and the starting log index for the current block.
+	txConfig TxConfig
+
+	// logs accumulates the Ethereum logs emitted so far in the current transaction, whether by
+	// the EVM's LOGn opcodes or by a precompile's Cosmos events (via plf).
+	logs []*ethtypes.Log
 }
 
 // returns a *statePlugin using the MultiStore belonging to ctx.
@@ -95,16 +134,22 @@ func NewPlugin(
 	bk BankKeeper,
 	evmStoreKey storetypes.StoreKey,
 	evmDenom string,
+	txConfig TxConfig,
 ) ethstate.Plugin {
 	sp := &statePlugin{
 		evmStoreKey: evmStoreKey,
 		ak:          ak,
 		bk:          bk,
 		evmDenom:    evmDenom,
+		journal:     newJournal(),
+		suicided:    make(map[common.Address]struct{}),
+		accessList:  newAccessList(),
+		txConfig:    txConfig,
 	}
 
-	// TODO: setup the PrecompileLogFactory here? or higher up?
-	sp.plf = nil
+	// build the PrecompileLogFactory, which converts Cosmos events emitted by precompiles into
+	// Ethereum logs and feeds them into sp.AddLog, bound to the TxConfig above.
+	sp.plf = events.NewPrecompileLogFactory(sp)
 
 	// setup the Controllable MultiStore and EventManager and attach them to the context
 	sp.cms = snapmulti.NewStoreFrom(ctx.MultiStore())
@@ -121,12 +166,43 @@ func NewPlugin(
 }
 
 // `Reset` implements `ethstate.StatePlugin`.
-func (sp *statePlugin) Reset(ctx context.Context) {
+func (sp *statePlugin) Reset(ctx context.Context, txConfig TxConfig) {
+	sp.txConfig = txConfig
+	sp.logs = nil
+
+	// rebuild the PrecompileLogFactory bound to the new TxConfig.
+	sp.plf = events.NewPrecompileLogFactory(sp)
+
 	// reset the Controllable MultiStore and EventManager and attach them to the context
 	sdkCtx := sdk.UnwrapSDKContext(ctx)
 	sp.cms = snapmulti.NewStoreFrom(sdkCtx.MultiStore())
 	cem := events.NewManagerFrom(sdkCtx.EventManager(), sp.plf)
 	sp.ctx = sdkCtx.WithMultiStore(sp.cms).WithEventManager(cem)
+
+	// reset the snapshot controller, now that the multi-store and event manager are new
+	ctrl := snapshot.NewController[string, libtypes.Controllable[string]]()
+	_ = ctrl.Register(sp.cms)
+	_ = ctrl.Register(cem)
+	sp.Controller = ctrl
+
+	// reset the per-tx journal and its bookkeeping.
+	sp.journal = newJournal()
+	sp.validRevisions = sp.validRevisions[:0]
+	sp.suicided = make(map[common.Address]struct{})
+	sp.refund = 0
+	sp.accessList = newAccessList()
+	sp.stateErr = nil
+}
+
+// `HasStateError` reports whether a bank or account keeper call has failed since the last
+// `Reset`, meaning every subsequent balance and nonce mutation is a no-op.
+func (sp *statePlugin) HasStateError() bool {
+	return sp.stateErr != nil
+}
+
+// `StateError` returns the error that tripped `stateErr`, or nil if none has occurred.
+func (sp *statePlugin) StateError() error {
+	return sp.stateErr
 }
 
 // `RegistryKey` implements `libtypes.Registrable`.
@@ -134,6 +210,39 @@ func (sp *statePlugin) RegistryKey() string {
 	return pluginRegistryKey
 }
 
+// =============================================================================
+// Snapshot / Revert
+// =============================================================================
+
+// `Snapshot` implements the `StatePlugin` interface by taking a snapshot of the underlying
+// multi-store/event manager (via the embedded `snapshot.Controller`) and tying it to the current
+// length of the journal, so that `RevertToSnapshot` can unwind both in lockstep.
+func (sp *statePlugin) Snapshot() int {
+	id := sp.Controller.Snapshot()
+	sp.validRevisions = append(sp.validRevisions, revision{id: id, journalIndex: sp.journal.length()})
+	return id
+}
+
+// `RevertToSnapshot` implements the `StatePlugin` interface by first replaying the journal
+// backwards to undo every mutation recorded since the given snapshot was taken, and then
+// reverting the underlying multi-store/event manager to the same checkpoint.
+func (sp *statePlugin) RevertToSnapshot(id int) {
+	idx := sort.Search(len(sp.validRevisions), func(i int) bool {
+		return sp.validRevisions[i].id >= id
+	})
+	if idx >= len(sp.validRevisions) || sp.validRevisions[idx].id != id {
+		panic(fmt.Sprintf("state: no snapshot for id %d", id))
+	}
+	snapshotIndex := sp.validRevisions[idx].journalIndex
+
+	// undo every journaled mutation recorded since the snapshot.
+	sp.journal.revert(sp, snapshotIndex)
+	sp.validRevisions = sp.validRevisions[:idx]
+
+	// revert the multi-store and event manager to the same checkpoint.
+	sp.Controller.RevertToSnapshot(id)
+}
+
 // ===========================================================================
 // Account
 // ===========================================================================
@@ -141,6 +250,22 @@ func (sp *statePlugin) RegistryKey() string {
 // CreateAccount implements the `StatePlugin` interface by creating a new account
 // in the account keeper. It will allow accounts to be overridden.
 func (sp *statePlugin) CreateAccount(addr common.Address) {
+	var prevAccount authtypes.AccountI
+	if sp.Exist(addr) {
+		// Overwrite semantics: an account already lives at addr (a CREATE2 collision, a
+		// suicide+recreate within the same tx, or a genesis overwrite), so wipe its prior
+		// storage and code before installing the new one, matching go-ethereum's
+		// `stateObject.reset`. The account's balance is intentionally preserved.
+		//
+		// `ResetAccount` journals its own effects (storage/code/codehash/refcount) and must be
+		// pushed before `createAccountChange` below, so that reverting undoes `createAccountChange`
+		// first (restoring only the auth account) and `ResetAccount`'s entry second (restoring
+		// storage/code/codehash/refcount), rather than the other way around.
+		prevAccount = sp.ak.GetAccount(sp.ctx, addr[:])
+		sp.ResetAccount(addr)
+	}
+	sp.journal.append(createAccountChange{account: addr, prevAccount: prevAccount})
+
 	acc := sp.ak.NewAccountWithAddress(sp.ctx, addr[:])
 
 	// save the new account in the account keeper
@@ -150,6 +275,53 @@ func (sp *statePlugin) CreateAccount(addr common.Address) {
 	sp.cms.GetKVStore(sp.evmStoreKey).Set(CodeHashKeyFor(addr), emptyCodeHashBytes)
 }
 
+// `ResetAccount` clears all storage slots and the code hash associated with addr, and releases its
+// reference to its code blob (deleting the blob only if no other account references it), without
+// touching its balance or removing the underlying auth account. It is used by
+// `CreateAccount` to implement go-ethereum's overwrite semantics when a contract is (re)deployed
+// to an address that already has state.
+//
+// Every mutation ResetAccount performs is journaled as a single `resetAccountChange` entry, so
+// that reverting a sub-call which (re)deployed over an existing account restores the original
+// storage, code, and code refcount exactly as they were beforehand.
+func (sp *statePlugin) ResetAccount(addr common.Address) {
+	store := sp.cms.GetKVStore(sp.evmStoreKey)
+
+	// snapshot every storage slot under addr before clearing it. Keys are collected before
+	// deleting, since mutating the store while an iterator over it is open is unsafe.
+	prefix := StorageKeyFor(addr)
+	it := sdk.KVStorePrefixIterator(store, prefix)
+	slots := make([]storageSlot, 0)
+	for ; it.Valid(); it.Next() {
+		slots = append(slots, storageSlot{
+			key:   common.BytesToHash(it.Key()[len(prefix):]),
+			value: common.BytesToHash(it.Value()),
+		})
+	}
+	it.Close()
+	for _, slot := range slots {
+		store.Delete(SlotKeyFor(addr, slot.key))
+	}
+
+	// snapshot addr's code before releasing its reference, so the refcount (and, if addr held the
+	// last reference, the code blob itself) can be restored on revert.
+	prevHash := sp.GetCodeHash(addr)
+	prevCode := sp.GetCode(addr)
+	if prevHash != emptyCodeHash && prevHash != (common.Hash{}) {
+		sp.decCodeRef(prevHash)
+	}
+
+	// clear the code hash.
+	store.Delete(CodeHashKeyFor(addr))
+
+	sp.journal.append(resetAccountChange{
+		account:  addr,
+		slots:    slots,
+		prevHash: prevHash,
+		prevCode: prevCode,
+	})
+}
+
 // `Exist` implements the `StatePlugin` interface by reporting whether the given account address
 // exists in the state. Notably this also returns true for suicided accounts, which is accounted
 // for since, `RemoveAccount()` is not called until Commit.
@@ -171,49 +343,87 @@ func (sp *statePlugin) GetBalance(addr common.Address) *big.Int {
 // from the account associated with addr. If the account does not exist, it will be
 // created.
 func (sp *statePlugin) AddBalance(addr common.Address, amount *big.Int) {
+	sp.journal.append(balanceChange{account: addr, amount: new(big.Int).Set(amount), wasAdd: true})
+	sp.addBalance(addr, amount)
+}
+
+// `addBalance` performs the actual mint-and-send for `AddBalance`, without touching the journal.
+// It is also used directly by the journal to revert a `SubBalance`.
+func (sp *statePlugin) addBalance(addr common.Address, amount *big.Int) {
+	if sp.HasStateError() {
+		return
+	}
+
 	coins := sdk.NewCoins(sdk.NewCoin(sp.evmDenom, sdk.NewIntFromBigInt(amount)))
 
 	// Mint the coins to the evm module account
 	if err := sp.bk.MintCoins(sp.ctx, EvmNamespace, coins); err != nil {
-		panic(err)
+		sp.stateErr = err
+		return
 	}
 
 	// Send the coins from the evm module account to the destination address.
 	if err := sp.bk.SendCoinsFromModuleToAccount(
 		sp.ctx, EvmNamespace, addr[:], coins,
 	); err != nil {
-		panic(err)
+		sp.stateErr = err
+		return
 	}
 }
 
 // SubBalance implements the `StatePlugin` interface by subtracting the given amount
 // from the account associated with addr.
 func (sp *statePlugin) SubBalance(addr common.Address, amount *big.Int) {
+	sp.journal.append(balanceChange{account: addr, amount: new(big.Int).Set(amount), wasAdd: false})
+	sp.subBalance(addr, amount)
+}
+
+// `subBalance` performs the actual send-and-burn for `SubBalance`, without touching the journal.
+// It is also used directly by the journal to revert an `AddBalance`.
+func (sp *statePlugin) subBalance(addr common.Address, amount *big.Int) {
+	if sp.HasStateError() {
+		return
+	}
+
 	coins := sdk.NewCoins(sdk.NewCoin(sp.evmDenom, sdk.NewIntFromBigInt(amount)))
 
 	// Send the coins from the source address to the evm module account.
 	if err := sp.bk.SendCoinsFromAccountToModule(
 		sp.ctx, addr[:], EvmNamespace, coins,
 	); err != nil {
-		panic(err)
+		sp.stateErr = err
+		return
 	}
 
 	// Burn the coins from the evm module account.
 	if err := sp.bk.BurnCoins(sp.ctx, EvmNamespace, coins); err != nil {
-		panic(err)
+		sp.stateErr = err
+		return
 	}
 }
 
 // `TransferBalance` sends the given amount from one account to another. It will
 // error if the sender does not have enough funds to send.
 func (sp *statePlugin) TransferBalance(from, to common.Address, amount *big.Int) {
+	sp.journal.append(transferBalanceChange{from: from, to: to, amount: new(big.Int).Set(amount)})
+	sp.transferBalance(from, to, amount)
+}
+
+// `transferBalance` performs the actual send for `TransferBalance`, without touching the journal.
+// It is also used directly by the journal to revert a `TransferBalance`.
+func (sp *statePlugin) transferBalance(from, to common.Address, amount *big.Int) {
+	if sp.HasStateError() {
+		return
+	}
+
 	coins := sdk.NewCoins(sdk.NewCoin(sp.evmDenom, sdk.NewIntFromBigInt(amount)))
 
 	// Send the coins from the source address to the destination address.
 	if err := sp.bk.SendCoins(sp.ctx, from[:], to[:], coins); err != nil {
-		// This is safe to panic as the error is only returned if the sender does
-		// not have enough funds to send, which should be guarded by `CanTransfer`.
-		panic(err)
+		// This should be guarded by `CanTransfer`, but a recoverable bank error is still
+		// possible; surface it via `stateErr` rather than panicking the node.
+		sp.stateErr = err
+		return
 	}
 }
 
@@ -234,6 +444,17 @@ func (sp *statePlugin) GetNonce(addr common.Address) uint64 {
 // SetNonce implements the `StatePlugin` interface by setting the nonce
 // of an account.
 func (sp *statePlugin) SetNonce(addr common.Address, nonce uint64) {
+	sp.journal.append(nonceChange{account: addr, prev: sp.GetNonce(addr)})
+	sp.setNonce(addr, nonce)
+}
+
+// `setNonce` performs the actual nonce update for `SetNonce`, without touching the journal. It is
+// also used directly by the journal to revert a `SetNonce`.
+func (sp *statePlugin) setNonce(addr common.Address, nonce uint64) {
+	if sp.HasStateError() {
+		return
+	}
+
 	// get the account or create a new one if doesn't exist
 	acc := sp.ak.GetAccount(sp.ctx, addr[:])
 	if acc == nil {
@@ -241,7 +462,8 @@ func (sp *statePlugin) SetNonce(addr common.Address, nonce uint64) {
 	}
 
 	if err := acc.SetSequence(nonce); err != nil {
-		panic(err)
+		sp.stateErr = err
+		return
 	}
 
 	sp.ak.SetAccount(sp.ctx, acc)
@@ -282,15 +504,35 @@ func (sp *statePlugin) GetCode(addr common.Address) []byte {
 // SetCode implements the `StatePlugin` interface by setting the code hash and
 // code for the given account.
 func (sp *statePlugin) SetCode(addr common.Address, code []byte) {
-	codeHash := crypto.Keccak256Hash(code)
-	ethStore := sp.cms.GetKVStore(sp.evmStoreKey)
-	ethStore.Set(CodeHashKeyFor(addr), codeHash[:])
+	sp.journal.append(codeChange{
+		account:  addr,
+		prevHash: sp.GetCodeHash(addr),
+		prevCode: sp.GetCode(addr),
+	})
+	sp.setCode(addr, crypto.Keccak256Hash(code), code)
+}
+
+// `setCode` performs the actual code hash/code update for `SetCode`, without touching the
+// journal. It is also used directly by the journal to revert a `SetCode`.
+//
+// Code blobs are stored once per unique hash and refcounted: setting addr's code hash to a value
+// increments that hash's refcount (writing the blob only on first insert), while moving addr away
+// from its previous hash decrements the old hash's refcount, deleting the blob once it drops to
+// zero. This lets N accounts share identical bytecode while still garbage collecting a blob once
+// every account referencing it has been suicided or redeployed with different code.
+func (sp *statePlugin) setCode(addr common.Address, codeHash common.Hash, code []byte) {
+	prevHash := sp.GetCodeHash(addr)
+	if prevHash == codeHash {
+		return
+	}
 
-	// store or delete code
-	if len(code) == 0 {
-		ethStore.Delete(CodeKeyFor(codeHash))
-	} else {
-		ethStore.Set(CodeKeyFor(codeHash), code)
+	sp.cms.GetKVStore(sp.evmStoreKey).Set(CodeHashKeyFor(addr), codeHash[:])
+
+	if len(code) > 0 {
+		sp.incCodeRef(codeHash, code)
+	}
+	if prevHash != emptyCodeHash && prevHash != (common.Hash{}) {
+		sp.decCodeRef(prevHash)
 	}
 }
 
@@ -339,7 +581,13 @@ func (sp *statePlugin) SetState(addr common.Address, key, value common.Hash) {
 	// hash.
 	//
 	// CONTRACT: never manually call SetState outside of `opSstore`, or InitGenesis.
+	sp.journal.append(storageChange{account: addr, key: key, prev: sp.GetState(addr, key)})
+	sp.setState(addr, key, value)
+}
 
+// `setState` performs the actual slot update for `SetState`, without touching the journal. It is
+// also used directly by the journal to revert a `SetState`.
+func (sp *statePlugin) setState(addr common.Address, key, value common.Hash) {
 	// If empty value is given, delete the state entry.
 	if len(value) == 0 || (value == common.Hash{}) {
 		sp.cms.GetKVStore(sp.evmStoreKey).Delete(SlotKeyFor(addr, key))
@@ -354,27 +602,66 @@ func (sp *statePlugin) SetState(addr common.Address, key, value common.Hash) {
 // ForEachStorage
 // =============================================================================
 
-// `ForEachStorage` implements the `StatePlugin` interface by iterating through the contract state
-// contract storage, the iteration order is not defined.
+// `ForEachStorage` implements the `StatePlugin` interface by iterating through the contract's
+// storage, the iteration order is not defined.
 //
-// Note: We do not support iterating through any storage that is modified before calling
-// `ForEachStorage`; only committed state is iterated through.
+// `GetKVStore` layers any pending (uncommitted) writes tracked by the snapshot cache above the
+// committed state, so this also surfaces slots written earlier in the current transaction, which
+// is required for `debug_traceCall`-style tooling and precompiles that enumerate a contract's
+// live storage mid-transaction.
 func (sp *statePlugin) ForEachStorage(
 	addr common.Address,
 	cb func(key, value common.Hash) bool,
 ) error {
-	it := sdk.KVStorePrefixIterator(
-		sp.cms.GetKVStore(sp.evmStoreKey),
-		StorageKeyFor(addr),
-	)
+	return sp.forEachStorage(addr, nil, cb)
+}
+
+// `ForEachStorageAt` is identical to `ForEachStorage`, except that iteration starts at `startKey`
+// (exclusive of anything before it) rather than from the beginning of the address' storage. This
+// is used by JSON-RPC to paginate through a contract's storage.
+func (sp *statePlugin) ForEachStorageAt(
+	addr common.Address,
+	startKey common.Hash,
+	cb func(key, value common.Hash) bool,
+) error {
+	return sp.forEachStorage(addr, startKey[:], cb)
+}
+
+// `forEachStorage` iterates the merged committed/dirty view of addr's storage, starting at
+// `start` (or from the beginning of the prefix, if `start` is empty), skipping tombstoned/empty
+// values and de-duplicating any key already visited.
+func (sp *statePlugin) forEachStorage(
+	addr common.Address,
+	start []byte,
+	cb func(key, value common.Hash) bool,
+) error {
+	prefix := StorageKeyFor(addr)
+
+	startKey := prefix
+	if len(start) > 0 {
+		startKey = append(append([]byte{}, prefix...), start...)
+	}
+
+	it := sp.cms.GetKVStore(sp.evmStoreKey).Iterator(startKey, storetypes.PrefixEndBytes(prefix))
 	defer it.Close()
 
+	visited := make(map[common.Hash]struct{})
 	for ; it.Valid(); it.Next() {
-		committedValue := it.Value()
-		if len(committedValue) > 0 {
-			if !cb(common.BytesToHash(it.Key()), common.BytesToHash(committedValue)) {
-				return nil // stop iteration
-			}
+		value := it.Value()
+		if len(value) == 0 {
+			// tombstoned/deleted slot, skip.
+			continue
+		}
+
+		key := common.BytesToHash(it.Key()[len(prefix):])
+		if _, seen := visited[key]; seen {
+			// already surfaced from the dirty layer above committed state.
+			continue
+		}
+		visited[key] = struct{}{}
+
+		if !cb(key, common.BytesToHash(value)) {
+			return nil // stop iteration
 		}
 	}
 
@@ -397,10 +684,136 @@ func (sp *statePlugin) DeleteSuicides(suicides []common.Address) {
 				return true
 			})
 
-		// clear the codehash from this account
+		// release this account's reference to its code blob, deleting it once no account
+		// references it anymore, and clear the codehash from this account.
+		if codeHash := sp.GetCodeHash(suicidalAddr); codeHash != emptyCodeHash && codeHash != (common.Hash{}) {
+			sp.decCodeRef(codeHash)
+		}
 		sp.cms.GetKVStore(sp.evmStoreKey).Delete(CodeHashKeyFor(suicidalAddr))
 
 		// remove auth account
 		sp.ak.RemoveAccount(sp.ctx, acct)
+
+		delete(sp.suicided, suicidalAddr)
+	}
+}
+
+// =============================================================================
+// Suicide
+// =============================================================================
+
+// `Suicide` implements the `StatePlugin` interface by marking the given address as suicided for
+// the remainder of the transaction. The account's storage/code/auth-account are not actually
+// removed until `DeleteSuicides` is called, once the outermost call frame has succeeded.
+func (sp *statePlugin) Suicide(addr common.Address) bool {
+	if !sp.Exist(addr) {
+		return false
+	}
+	sp.journal.append(suicideChange{account: addr})
+	sp.suicided[addr] = struct{}{}
+	return true
+}
+
+// `HasSuicided` implements the `StatePlugin` interface by reporting whether the given address was
+// marked as suicided during the current transaction.
+func (sp *statePlugin) HasSuicided(addr common.Address) bool {
+	_, suicided := sp.suicided[addr]
+	return suicided
+}
+
+// =============================================================================
+// Refund
+// =============================================================================
+
+// `AddRefund` implements the `StatePlugin` interface by adding the given amount of gas to the
+// refund counter.
+func (sp *statePlugin) AddRefund(gas uint64) {
+	sp.journal.append(refundChange{prev: sp.refund})
+	sp.refund += gas
+}
+
+// `SubRefund` implements the `StatePlugin` interface by removing the given amount of gas from the
+// refund counter. It panics if the refund counter would go negative, since that indicates a bug
+// in the EVM's gas accounting.
+func (sp *statePlugin) SubRefund(gas uint64) {
+	sp.journal.append(refundChange{prev: sp.refund})
+	if gas > sp.refund {
+		panic("state: refund counter below zero")
 	}
+	sp.refund -= gas
+}
+
+// `GetRefund` implements the `StatePlugin` interface by returning the current value of the refund
+// counter.
+func (sp *statePlugin) GetRefund() uint64 {
+	return sp.refund
+}
+
+// =============================================================================
+// Access List
+// =============================================================================
+
+// `AddressInAccessList` implements the `StatePlugin` interface.
+func (sp *statePlugin) AddressInAccessList(addr common.Address) bool {
+	return sp.accessList.ContainsAddress(addr)
+}
+
+// `SlotInAccessList` implements the `StatePlugin` interface.
+func (sp *statePlugin) SlotInAccessList(
+	addr common.Address, slot common.Hash,
+) (addressPresent, slotPresent bool) {
+	return sp.accessList.Contains(addr, slot)
+}
+
+// `AddAddressToAccessList` implements the `StatePlugin` interface by adding addr to the access
+// list, journaling the change if addr was not already present.
+func (sp *statePlugin) AddAddressToAccessList(addr common.Address) {
+	if sp.accessList.AddAddress(addr) {
+		sp.journal.append(accessListAddAccountChange{address: addr})
+	}
+}
+
+// `AddSlotToAccessList` implements the `StatePlugin` interface by adding the (addr, slot) pair to
+// the access list, journaling whichever of the address/slot were not already present.
+func (sp *statePlugin) AddSlotToAccessList(addr common.Address, slot common.Hash) {
+	addrMod, slotMod := sp.accessList.AddSlot(addr, slot)
+	if addrMod {
+		sp.journal.append(accessListAddAccountChange{address: addr})
+	}
+	if slotMod {
+		sp.journal.append(accessListAddSlotChange{address: addr, slot: slot})
+	}
+}
+
+// =============================================================================
+// Logs
+// =============================================================================
+
+// `AddLog` implements the `StatePlugin` interface by stamping log with the current TxConfig and
+// appending it to the logs emitted so far in the current transaction. `log.Index` is set to the
+// log's position within the block, not within the transaction, matching go-ethereum semantics.
+// It is also called by the `PrecompileLogFactory` to surface precompile-emitted Cosmos events as
+// Ethereum logs.
+func (sp *statePlugin) AddLog(log *ethtypes.Log) {
+	log.BlockHash = sp.txConfig.BlockHash
+	log.TxHash = sp.txConfig.TxHash
+	log.TxIndex = sp.txConfig.TxIndex
+	log.Index = sp.txConfig.LogIndex + uint(len(sp.logs))
+
+	sp.journal.append(addLogChange{})
+	sp.logs = append(sp.logs, log)
+}
+
+// `Logs` implements the `StatePlugin` interface by returning every log emitted so far in the
+// current transaction, in the order they were emitted.
+func (sp *statePlugin) Logs() []*ethtypes.Log {
+	return sp.logs
+}
+
+// `SetTxContext` implements the `StatePlugin` interface by recording the hash and index of the
+// transaction about to be executed, so that logs emitted during its execution are stamped
+// correctly. It must be called before processing each transaction in a block.
+func (sp *statePlugin) SetTxContext(txHash common.Hash, txIndex uint) {
+	sp.txConfig.TxHash = txHash
+	sp.txConfig.TxIndex = txIndex
 }
\ No newline at end of file
@@ -0,0 +1,111 @@
+// Copyright (C) 2023, Berachain Foundation. All rights reserved.
+// See the file LICENSE for licensing terms.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package state
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/berachain/stargazer/lib/common"
+)
+
+// TestCreateAccountOverwriteResetsStorageAndCode mimics a CREATE2 collision: a contract already
+// lives at addr with storage and code, and CreateAccount is called again for the same address.
+// The prior storage/code must be wiped (go-ethereum's `stateObject.reset` semantics).
+func TestCreateAccountOverwriteResetsStorageAndCode(t *testing.T) {
+	sp := newTestStatePlugin()
+	addr := common.BytesToAddress([]byte{0x1})
+	key := common.BytesToHash([]byte{0x2})
+
+	sp.CreateAccount(addr)
+	sp.SetCode(addr, []byte{0xAA})
+	sp.SetState(addr, key, common.BytesToHash([]byte{0xBB}))
+	require.True(t, sp.Exist(addr))
+
+	sp.CreateAccount(addr)
+
+	require.Equal(t, emptyCodeHash, sp.GetCodeHash(addr))
+	require.Nil(t, sp.GetCode(addr))
+	require.Equal(t, common.Hash{}, sp.GetState(addr, key))
+}
+
+// TestCreateAccountOverwriteRevertRestoresPriorStorageAndCode is the regression test for the
+// bug where CreateAccount's overwrite path (via ResetAccount) mutated storage/code/codehash
+// directly, with no journal entry, so reverting only undid the bare createAccountChange and left
+// the original account's storage/code permanently lost. This mimics suicide+recreate within a
+// sub-call that reverts: the pre-existing account's storage and code must come back exactly.
+func TestCreateAccountOverwriteRevertRestoresPriorStorageAndCode(t *testing.T) {
+	sp := newTestStatePlugin()
+	addr := common.BytesToAddress([]byte{0x1})
+	key := common.BytesToHash([]byte{0x2})
+	originalCode := []byte{0xAA, 0xBB}
+	originalValue := common.BytesToHash([]byte{0xCC})
+
+	sp.CreateAccount(addr)
+	sp.SetCode(addr, originalCode)
+	sp.SetState(addr, key, originalValue)
+	originalCodeHash := sp.GetCodeHash(addr)
+
+	// enter a sub-call: suicide addr, then redeploy (CREATE2) at the same address.
+	snapshot := sp.journal.length()
+	require.True(t, sp.Suicide(addr))
+	sp.CreateAccount(addr)
+	sp.SetCode(addr, []byte{0xDD})
+	sp.SetState(addr, key, common.BytesToHash([]byte{0xEE}))
+
+	// the sub-call reverts: everything it did, including the overwrite, must be undone.
+	sp.journal.revert(sp, snapshot)
+
+	require.False(t, sp.HasSuicided(addr))
+	require.True(t, sp.Exist(addr))
+	require.Equal(t, originalCodeHash, sp.GetCodeHash(addr))
+	require.Equal(t, originalCode, sp.GetCode(addr))
+	require.Equal(t, originalValue, sp.GetState(addr, key))
+}
+
+// TestCreateAccountFreshRevertRemovesAccount verifies the non-overwrite path: reverting a
+// CreateAccount for a brand-new address removes the account entirely, rather than trying to
+// restore a prior account that never existed.
+func TestCreateAccountFreshRevertRemovesAccount(t *testing.T) {
+	sp := newTestStatePlugin()
+	addr := common.BytesToAddress([]byte{0x1})
+
+	snapshot := sp.journal.length()
+	sp.CreateAccount(addr)
+	require.True(t, sp.Exist(addr))
+
+	sp.journal.revert(sp, snapshot)
+
+	require.False(t, sp.Exist(addr))
+}
+
+// TestResetAccountPreservesBalance verifies ResetAccount's documented contract: it clears storage
+// and code, but never touches the account's balance.
+func TestResetAccountPreservesBalance(t *testing.T) {
+	sp := newTestStatePlugin()
+	addr := common.BytesToAddress([]byte{0x1})
+
+	sp.CreateAccount(addr)
+	sp.SetCode(addr, []byte{0xAA})
+	bk, ok := sp.bk.(*fakeBankKeeper)
+	require.True(t, ok)
+	bk.balances[string(addr[:])] = bk.balances[string(addr[:])].Add(sdk.NewInt64Coin(sp.evmDenom, 42))
+
+	sp.ResetAccount(addr)
+
+	require.Equal(t, int64(42), sp.GetBalance(addr).Int64())
+}
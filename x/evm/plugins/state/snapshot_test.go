@@ -0,0 +1,112 @@
+// Copyright (C) 2023, Berachain Foundation. All rights reserved.
+// See the file LICENSE for licensing terms.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/berachain/stargazer/lib/common"
+)
+
+// TestSnapshotRevertSubCallIsolation is this package's acceptance test for chunk0-1: contract A
+// calls contract B, B's call reverts, and only B's mutations made during its call are undone,
+// while A's writes both before and after the sub-call persist. Unlike the journal-level revert
+// tests elsewhere in this package (which drive `sp.journal.revert` directly), this one goes
+// through the public
+// `sp.Snapshot`/`sp.RevertToSnapshot` API, so it also exercises the `validRevisions` bookkeeping
+// and the interaction between the journal's per-mutation undo and the underlying `snapshot.
+// Controller`'s wholesale store revert (plugin.go:229-244).
+func TestSnapshotRevertSubCallIsolation(t *testing.T) {
+	sp := newTestStatePlugin()
+	a := common.BytesToAddress([]byte{0xA})
+	b := common.BytesToAddress([]byte{0xB})
+	slot := common.BytesToHash([]byte{0x1})
+
+	sp.CreateAccount(a)
+	sp.CreateAccount(b)
+
+	// A's state before the sub-call.
+	sp.AddBalance(a, bigIntFor(t, 100))
+	sp.SetNonce(a, 1)
+	sp.SetState(a, slot, common.BytesToHash([]byte{0xAA}))
+
+	// A calls B: B's call is a sub-call, represented by a nested snapshot.
+	subCallID := sp.Snapshot()
+	sp.AddBalance(b, bigIntFor(t, 50))
+	sp.SetNonce(b, 1)
+	sp.SetState(b, slot, common.BytesToHash([]byte{0xBB}))
+	require.True(t, sp.Suicide(b))
+
+	// B's call reverts.
+	sp.RevertToSnapshot(subCallID)
+
+	// A's writes before the sub-call persist.
+	require.Zero(t, sp.GetBalance(a).Cmp(bigIntFor(t, 100)))
+	require.EqualValues(t, 1, sp.GetNonce(a))
+	require.Equal(t, common.BytesToHash([]byte{0xAA}), sp.GetState(a, slot))
+
+	// B's writes made during the reverted sub-call are gone.
+	require.Zero(t, sp.GetBalance(b).Sign())
+	require.EqualValues(t, 0, sp.GetNonce(b))
+	require.Equal(t, common.Hash{}, sp.GetState(b, slot))
+	require.False(t, sp.HasSuicided(b))
+
+	// A's writes made after the reverted sub-call still persist.
+	sp.AddBalance(a, bigIntFor(t, 25))
+	require.Zero(t, sp.GetBalance(a).Cmp(bigIntFor(t, 125)))
+}
+
+// TestSnapshotNestedRevertOnlyUndoesInnermostSubCall verifies that reverting an inner snapshot
+// leaves an outer (still-open) snapshot's mutations intact, matching the nested-call semantics
+// the EVM interpreter relies on.
+func TestSnapshotNestedRevertOnlyUndoesInnermostSubCall(t *testing.T) {
+	sp := newTestStatePlugin()
+	addr := common.BytesToAddress([]byte{0x1})
+	sp.CreateAccount(addr)
+
+	outerID := sp.Snapshot()
+	sp.SetNonce(addr, 1)
+
+	innerID := sp.Snapshot()
+	sp.SetNonce(addr, 2)
+
+	sp.RevertToSnapshot(innerID)
+	require.EqualValues(t, 1, sp.GetNonce(addr))
+
+	// the outer snapshot must still be valid and, if later reverted, undo the outer mutation too.
+	sp.RevertToSnapshot(outerID)
+	require.EqualValues(t, 0, sp.GetNonce(addr))
+}
+
+// TestRevertToSnapshotPanicsOnUnknownID verifies the `validRevisions` binary search rejects an id
+// that was never handed out by `Snapshot` (e.g. already reverted past, or entirely made up).
+func TestRevertToSnapshotPanicsOnUnknownID(t *testing.T) {
+	sp := newTestStatePlugin()
+
+	id := sp.Snapshot()
+	sp.RevertToSnapshot(id)
+
+	require.Panics(t, func() {
+		sp.RevertToSnapshot(id)
+	})
+}
+
+func bigIntFor(t *testing.T, v int64) *big.Int {
+	t.Helper()
+	return big.NewInt(v)
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2023, Berachain Foundation. All rights reserved.
+// See the file LICENSE for licensing terms.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/berachain/stargazer/lib/common"
+)
+
+// TestForEachStorageIncludesDirtyState writes a slot, then iterates, verifying that a value
+// written in the current (uncommitted) transaction is surfaced without needing a commit.
+func TestForEachStorageIncludesDirtyState(t *testing.T) {
+	sp := newTestStatePlugin()
+	addr := common.BytesToAddress([]byte{0x1})
+	key := common.BytesToHash([]byte{0x2})
+	value := common.BytesToHash([]byte{0x3})
+
+	sp.SetState(addr, key, value)
+
+	seen := make(map[common.Hash]common.Hash)
+	require.NoError(t, sp.ForEachStorage(addr, func(k, v common.Hash) bool {
+		seen[k] = v
+		return true
+	}))
+
+	require.Equal(t, value, seen[key])
+}
+
+// TestForEachStorageSkipsDeletedSlots verifies that a slot set and then cleared back to the zero
+// value (a tombstone, per `setState`) is not surfaced by ForEachStorage.
+func TestForEachStorageSkipsDeletedSlots(t *testing.T) {
+	sp := newTestStatePlugin()
+	addr := common.BytesToAddress([]byte{0x1})
+	key := common.BytesToHash([]byte{0x2})
+
+	sp.SetState(addr, key, common.BytesToHash([]byte{0x3}))
+	sp.SetState(addr, key, common.Hash{})
+
+	var count int
+	require.NoError(t, sp.ForEachStorage(addr, func(common.Hash, common.Hash) bool {
+		count++
+		return true
+	}))
+	require.Zero(t, count)
+}
+
+// TestForEachStorageDoesNotLeakOtherAccounts verifies that ForEachStorage for one address never
+// surfaces a slot belonging to another address sharing the same store.
+func TestForEachStorageDoesNotLeakOtherAccounts(t *testing.T) {
+	sp := newTestStatePlugin()
+	addrA := common.BytesToAddress([]byte{0x1})
+	addrB := common.BytesToAddress([]byte{0x2})
+	key := common.BytesToHash([]byte{0x9})
+
+	sp.SetState(addrA, key, common.BytesToHash([]byte{0xAA}))
+	sp.SetState(addrB, key, common.BytesToHash([]byte{0xBB}))
+
+	var gotA common.Hash
+	require.NoError(t, sp.ForEachStorage(addrA, func(k, v common.Hash) bool {
+		require.Equal(t, key, k)
+		gotA = v
+		return true
+	}))
+	require.Equal(t, common.BytesToHash([]byte{0xAA}), gotA)
+}
+
+// TestForEachStorageDirtyOverrideShadowsCommittedValue verifies the scenario described in
+// ForEachStorage's doc comment: a slot that was already present in the committed store before the
+// current transaction, and is then overwritten by a dirty write during the transaction, must be
+// surfaced exactly once, with the dirty value, not the stale committed one and not both.
+//
+// `fakeMultiStore`'s `live` and `committed` stores are independent (real `snapmulti.Store` layers
+// the dirty cache transparently over the committed view instead), so this test seeds `committed`
+// directly via `GetCommittedKVStore` to represent the pre-tx value, and separately seeds `live`
+// with the overriding value via `SetState`, the same way `snapmulti.Store`'s live view would read
+// back once the dirty cache held an override. `ForEachStorage`, which only ever iterates
+// `GetKVStore` (see plugin.go), must then see only the dirty value for that slot.
+func TestForEachStorageDirtyOverrideShadowsCommittedValue(t *testing.T) {
+	sp := newTestStatePlugin()
+	addr := common.BytesToAddress([]byte{0x1})
+	key := common.BytesToHash([]byte{0x2})
+	committedValue := common.BytesToHash([]byte{0xC0})
+	dirtyValue := common.BytesToHash([]byte{0xD1})
+
+	sp.cms.GetCommittedKVStore(sp.evmStoreKey).Set(SlotKeyFor(addr, key), committedValue[:])
+	sp.SetState(addr, key, dirtyValue)
+
+	seen := make(map[common.Hash][]common.Hash)
+	require.NoError(t, sp.ForEachStorage(addr, func(k, v common.Hash) bool {
+		seen[k] = append(seen[k], v)
+		return true
+	}))
+
+	require.Len(t, seen[key], 1)
+	require.Equal(t, dirtyValue, seen[key][0])
+}
+
+// TestForEachStorageAtStartsAtStartKey verifies the JSON-RPC pagination variant resumes iteration
+// at the supplied start key (inclusive), skipping any slot sorting before it.
+func TestForEachStorageAtStartsAtStartKey(t *testing.T) {
+	sp := newTestStatePlugin()
+	addr := common.BytesToAddress([]byte{0x1})
+	keyA := common.BytesToHash([]byte{0x1})
+	keyB := common.BytesToHash([]byte{0x2})
+
+	sp.SetState(addr, keyA, common.BytesToHash([]byte{0xAA}))
+	sp.SetState(addr, keyB, common.BytesToHash([]byte{0xBB}))
+
+	var keys []common.Hash
+	require.NoError(t, sp.ForEachStorageAt(addr, keyA, func(k, v common.Hash) bool {
+		keys = append(keys, k)
+		return true
+	}))
+
+	require.Equal(t, []common.Hash{keyA, keyB}, keys)
+}
@@ -0,0 +1,238 @@
+// Copyright (C) 2023, Berachain Foundation. All rights reserved.
+// See the file LICENSE for licensing terms.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/store/dbadapter"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	tmdb "github.com/tendermint/tm-db"
+
+	"github.com/berachain/stargazer/lib/common"
+	"github.com/berachain/stargazer/lib/snapshot"
+	libtypes "github.com/berachain/stargazer/lib/types"
+)
+
+// errKeeperFailure is returned by the fake keepers below when a test arms them to fail, to
+// exercise the `stateErr`/`HasStateError` no-panic path.
+var errKeeperFailure = errors.New("state_test: simulated keeper failure")
+
+// `fakeMultiStore` is a minimal `ControllableMultiStore` backed by two independent in-memory
+// stores, standing in for the dirty (live) and committed views that `snapmulti` provides in the
+// real binary. It also implements `libtypes.Controllable[string]` (`RegistryKey`/`Snapshot`/
+// `RevertToSnapshot`), the same way `snapmulti.Store` does, so it can be registered with a real
+// `snapshot.Controller` and driven through `statePlugin`'s public `Snapshot`/`RevertToSnapshot`.
+type fakeMultiStore struct {
+	live      storetypes.KVStore
+	committed storetypes.KVStore
+
+	snapshotIDs []string
+	snapshots   []map[string][]byte
+}
+
+func newFakeMultiStore() *fakeMultiStore {
+	return &fakeMultiStore{
+		live:      dbadapter.Store{DB: tmdb.NewMemDB()},
+		committed: dbadapter.Store{DB: tmdb.NewMemDB()},
+	}
+}
+
+func (ms *fakeMultiStore) GetKVStore(storetypes.StoreKey) storetypes.KVStore { return ms.live }
+func (ms *fakeMultiStore) GetCommittedKVStore(storetypes.StoreKey) storetypes.KVStore {
+	return ms.committed
+}
+
+func (ms *fakeMultiStore) RegistryKey() string { return "fakeMultiStore" }
+
+// `Snapshot` records every key/value currently in the live store, so `RevertToSnapshot` can
+// restore exactly this content later.
+func (ms *fakeMultiStore) Snapshot() string {
+	snap := make(map[string][]byte)
+	it := sdk.KVStorePrefixIterator(ms.live, []byte{})
+	for ; it.Valid(); it.Next() {
+		snap[string(it.Key())] = append([]byte{}, it.Value()...)
+	}
+	it.Close()
+
+	id := fmt.Sprintf("fake-snapshot-%d", len(ms.snapshots))
+	ms.snapshotIDs = append(ms.snapshotIDs, id)
+	ms.snapshots = append(ms.snapshots, snap)
+	return id
+}
+
+// `RevertToSnapshot` restores the live store to exactly the content recorded by the `Snapshot`
+// call that produced id, discarding any later snapshots.
+func (ms *fakeMultiStore) RevertToSnapshot(id string) {
+	idx := -1
+	for i, snapID := range ms.snapshotIDs {
+		if snapID == id {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		panic("fakeMultiStore: no snapshot for id " + id)
+	}
+
+	it := sdk.KVStorePrefixIterator(ms.live, []byte{})
+	keys := make([][]byte, 0)
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, append([]byte{}, it.Key()...))
+	}
+	it.Close()
+	for _, key := range keys {
+		ms.live.Delete(key)
+	}
+
+	for key, value := range ms.snapshots[idx] {
+		ms.live.Set([]byte(key), value)
+	}
+
+	ms.snapshotIDs = ms.snapshotIDs[:idx]
+	ms.snapshots = ms.snapshots[:idx]
+}
+
+// `fakeAccountKeeper` is a minimal in-memory `AccountKeeper` for tests.
+type fakeAccountKeeper struct {
+	accounts map[string]authtypes.AccountI
+}
+
+func newFakeAccountKeeper() *fakeAccountKeeper {
+	return &fakeAccountKeeper{accounts: make(map[string]authtypes.AccountI)}
+}
+
+func (ak *fakeAccountKeeper) GetAccount(_ sdk.Context, addr []byte) authtypes.AccountI {
+	return ak.accounts[string(addr)]
+}
+
+func (ak *fakeAccountKeeper) SetAccount(_ sdk.Context, acc authtypes.AccountI) {
+	ak.accounts[string(acc.GetAddress())] = acc
+}
+
+func (ak *fakeAccountKeeper) NewAccountWithAddress(_ sdk.Context, addr []byte) authtypes.AccountI {
+	return authtypes.NewBaseAccountWithAddress(sdk.AccAddress(addr))
+}
+
+func (ak *fakeAccountKeeper) HasAccount(_ sdk.Context, addr []byte) bool {
+	_, ok := ak.accounts[string(addr)]
+	return ok
+}
+
+func (ak *fakeAccountKeeper) RemoveAccount(_ sdk.Context, acc authtypes.AccountI) {
+	delete(ak.accounts, string(acc.GetAddress()))
+}
+
+// `fakeBankKeeper` is a minimal in-memory `BankKeeper` for tests, which can be armed to fail the
+// next call so tests can assert the plugin surfaces the error via `stateErr` instead of panicking.
+type fakeBankKeeper struct {
+	balances map[string]sdk.Coins
+	failNext bool
+}
+
+func newFakeBankKeeper() *fakeBankKeeper {
+	return &fakeBankKeeper{balances: make(map[string]sdk.Coins)}
+}
+
+func (bk *fakeBankKeeper) maybeFail() error {
+	if bk.failNext {
+		bk.failNext = false
+		return errKeeperFailure
+	}
+	return nil
+}
+
+func (bk *fakeBankKeeper) GetBalance(_ sdk.Context, addr []byte, denom string) sdk.Coin {
+	return sdk.NewCoin(denom, bk.balances[string(addr)].AmountOf(denom))
+}
+
+func (bk *fakeBankKeeper) MintCoins(_ sdk.Context, module string, amt sdk.Coins) error {
+	if err := bk.maybeFail(); err != nil {
+		return err
+	}
+	bk.balances[module] = bk.balances[module].Add(amt...)
+	return nil
+}
+
+func (bk *fakeBankKeeper) BurnCoins(_ sdk.Context, module string, amt sdk.Coins) error {
+	if err := bk.maybeFail(); err != nil {
+		return err
+	}
+	bk.balances[module] = bk.balances[module].Sub(amt)
+	return nil
+}
+
+func (bk *fakeBankKeeper) SendCoinsFromModuleToAccount(
+	_ sdk.Context, module string, addr []byte, amt sdk.Coins,
+) error {
+	if err := bk.maybeFail(); err != nil {
+		return err
+	}
+	bk.balances[module] = bk.balances[module].Sub(amt)
+	bk.balances[string(addr)] = bk.balances[string(addr)].Add(amt...)
+	return nil
+}
+
+func (bk *fakeBankKeeper) SendCoinsFromAccountToModule(
+	_ sdk.Context, addr []byte, module string, amt sdk.Coins,
+) error {
+	if err := bk.maybeFail(); err != nil {
+		return err
+	}
+	bk.balances[string(addr)] = bk.balances[string(addr)].Sub(amt)
+	bk.balances[module] = bk.balances[module].Add(amt...)
+	return nil
+}
+
+func (bk *fakeBankKeeper) SendCoins(_ sdk.Context, from, to []byte, amt sdk.Coins) error {
+	if err := bk.maybeFail(); err != nil {
+		return err
+	}
+	bk.balances[string(from)] = bk.balances[string(from)].Sub(amt)
+	bk.balances[string(to)] = bk.balances[string(to)].Add(amt...)
+	return nil
+}
+
+// `newTestStatePlugin` builds a `*statePlugin` wired to the fakes above, bypassing `NewPlugin`
+// (whose `snapmulti`/`events` wiring lives outside this package) so tests can drive the plugin's
+// own logic directly. It registers the fake multi-store with a real `snapshot.Controller`, the
+// same way `NewPlugin` registers the real one, so tests can exercise `sp.Snapshot`/
+// `sp.RevertToSnapshot` through the public API rather than only `sp.journal.revert`.
+func newTestStatePlugin() *statePlugin {
+	cms := newFakeMultiStore()
+
+	ctrl := snapshot.NewController[string, libtypes.Controllable[string]]()
+	_ = ctrl.Register(cms)
+
+	sp := &statePlugin{
+		ctx:         sdk.Context{},
+		cms:         cms,
+		evmStoreKey: evmStoreKeyForTest,
+		ak:          newFakeAccountKeeper(),
+		bk:          newFakeBankKeeper(),
+		evmDenom:    "abera",
+		journal:     newJournal(),
+		suicided:    make(map[common.Address]struct{}),
+		accessList:  newAccessList(),
+		txConfig:    EmptyTxConfig(common.Hash{}),
+	}
+	sp.Controller = ctrl
+	return sp
+}
+
+var evmStoreKeyForTest = sdk.NewKVStoreKey(EvmNamespace)
@@ -0,0 +1,36 @@
+// Copyright (C) 2023, Berachain Foundation. All rights reserved.
+// See the file LICENSE for licensing terms.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package state
+
+import "github.com/berachain/stargazer/lib/common"
+
+// `TxConfig` carries the per-transaction metadata required to stamp Ethereum logs with the
+// correct identifiers. `LogIndex` is the number of logs already emitted earlier in the block
+// (not the tx), matching go-ethereum semantics, and is advanced by the caller before processing
+// each subsequent transaction in a block.
+type TxConfig struct {
+	BlockHash common.Hash
+	TxHash    common.Hash
+	TxIndex   uint
+	LogIndex  uint
+}
+
+// `EmptyTxConfig` returns a `TxConfig` carrying only a block hash, for use before `SetTxContext`
+// has been called for a specific transaction (e.g. `eth_call`).
+func EmptyTxConfig(blockHash common.Hash) TxConfig {
+	return TxConfig{
+		BlockHash: blockHash,
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2023, Berachain Foundation. All rights reserved.
+// See the file LICENSE for licensing terms.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ethtypes "github.com/berachain/stargazer/eth/core/types"
+	"github.com/berachain/stargazer/lib/common"
+)
+
+// TestAddLogStampsBlockAndTxMetadata verifies AddLog stamps each log with the current TxConfig's
+// block/tx identifiers.
+func TestAddLogStampsBlockAndTxMetadata(t *testing.T) {
+	sp := newTestStatePlugin()
+	blockHash := common.BytesToHash([]byte{0x1})
+	txHash := common.BytesToHash([]byte{0x2})
+	sp.txConfig = TxConfig{BlockHash: blockHash, TxHash: txHash, TxIndex: 3, LogIndex: 5}
+
+	log := &ethtypes.Log{}
+	sp.AddLog(log)
+
+	require.Equal(t, blockHash, log.BlockHash)
+	require.Equal(t, txHash, log.TxHash)
+	require.EqualValues(t, 3, log.TxIndex)
+}
+
+// TestAddLogIndexesByBlockNotTx verifies log.Index counts from TxConfig.LogIndex (the number of
+// logs already emitted earlier in the block), not from zero within the current tx.
+func TestAddLogIndexesByBlockNotTx(t *testing.T) {
+	sp := newTestStatePlugin()
+	sp.txConfig = TxConfig{LogIndex: 5}
+
+	first := &ethtypes.Log{}
+	sp.AddLog(first)
+	second := &ethtypes.Log{}
+	sp.AddLog(second)
+
+	require.EqualValues(t, 5, first.Index)
+	require.EqualValues(t, 6, second.Index)
+	require.Equal(t, []*ethtypes.Log{first, second}, sp.Logs())
+}
+
+// TestAddLogRevert verifies that reverting an AddLog pops only the reverted log, leaving logs
+// emitted by earlier, non-reverted calls untouched.
+func TestAddLogRevert(t *testing.T) {
+	sp := newTestStatePlugin()
+
+	kept := &ethtypes.Log{}
+	sp.AddLog(kept)
+
+	snapshot := sp.journal.length()
+	sp.AddLog(&ethtypes.Log{})
+	require.Len(t, sp.Logs(), 2)
+
+	sp.journal.revert(sp, snapshot)
+
+	require.Equal(t, []*ethtypes.Log{kept}, sp.Logs())
+}
+
+// TestSetTxContextKeepsCallerLogIndex verifies SetTxContext updates the tx hash/index
+// while leaving the caller-supplied starting LogIndex (set between transactions in a block) in
+// place for the next transaction's logs.
+func TestSetTxContextKeepsCallerLogIndex(t *testing.T) {
+	sp := newTestStatePlugin()
+	sp.txConfig.LogIndex = 10
+
+	txHash := common.BytesToHash([]byte{0x3})
+	sp.SetTxContext(txHash, 2)
+
+	log := &ethtypes.Log{}
+	sp.AddLog(log)
+
+	require.Equal(t, txHash, log.TxHash)
+	require.EqualValues(t, 2, log.TxIndex)
+	require.EqualValues(t, 10, log.Index)
+}